@@ -0,0 +1,188 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecloudhq/fnserve/runtime"
+)
+
+// Invoker executes a named function and returns its result, independent of
+// how that function is actually wired up (host, Docker, warm pool, ...).
+type Invoker func(ctx context.Context, function string, event []byte, fnCtx runtime.Context) ([]byte, error)
+
+const pollInterval = 250 * time.Millisecond
+
+// Queue runs async jobs with a concurrency independent of the server's sync
+// request semaphore, retrying failures with exponential backoff and moving
+// jobs that exhaust their retries to the dead-letter state.
+type Queue struct {
+	Store      *Store
+	Invoke     Invoker
+	MaxRetries int
+
+	// Timeout bounds each job invocation, mirroring the sync path's
+	// RequestTimeout. Without it a hung function would pin an async
+	// semaphore slot and its job forever, never failing, retrying, or
+	// reaching the dead-letter state.
+	Timeout time.Duration
+
+	sem  chan struct{}
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewQueue creates a queue backed by store. concurrency bounds how many
+// jobs run at once; maxRetries bounds how many times a failing job is
+// retried before it is moved to the dead-letter state; timeout bounds each
+// job invocation.
+func NewQueue(store *Store, invoke Invoker, concurrency, maxRetries int, timeout time.Duration) *Queue {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Queue{
+		Store:      store,
+		Invoke:     invoke,
+		MaxRetries: maxRetries,
+		Timeout:    timeout,
+		sem:        make(chan struct{}, concurrency),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Enqueue persists a new queued job and returns it.
+func (q *Queue) Enqueue(function string, event []byte, params, env map[string]string) (Job, error) {
+	now := time.Now()
+	job := Job{
+		ID:        "job-" + uuid.NewString(),
+		Function:  function,
+		Event:     event,
+		Params:    params,
+		Env:       env,
+		State:     StateQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := q.Store.Put(job); err != nil {
+		return Job{}, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return job, nil
+}
+
+// Run polls for ready jobs and dispatches them until ctx is canceled or
+// Stop is called.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.wg.Wait()
+			return
+		case <-q.stop:
+			q.wg.Wait()
+			return
+		case <-ticker.C:
+			q.dispatchReady(ctx)
+		}
+	}
+}
+
+// Stop halts the poll loop. In-flight jobs are allowed to finish.
+func (q *Queue) Stop() {
+	close(q.stop)
+}
+
+func (q *Queue) dispatchReady(ctx context.Context) {
+	candidates, err := q.Store.ListReady(time.Now())
+	if err != nil {
+		fmt.Printf("[async] failed to list ready jobs: %v\n", err)
+		return
+	}
+
+	for _, candidate := range candidates {
+		select {
+		case q.sem <- struct{}{}:
+		default:
+			// At capacity for this tick; the job stays queued and is
+			// picked up on a later tick.
+			continue
+		}
+
+		// Claim atomically flips queued -> running, so a job already
+		// claimed by another tick (or no longer queued at all) is
+		// skipped here instead of being invoked twice.
+		job, claimed, err := q.Store.Claim(candidate.ID)
+		if err != nil {
+			fmt.Printf("[async] failed to claim job %s: %v\n", candidate.ID, err)
+			<-q.sem
+			continue
+		}
+		if !claimed {
+			<-q.sem
+			continue
+		}
+
+		q.wg.Add(1)
+		go func(job Job) {
+			defer q.wg.Done()
+			defer func() { <-q.sem }()
+			q.process(ctx, job)
+		}(job)
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job Job) {
+	fnCtx := runtime.Context{
+		RequestID:  job.ID,
+		Timestamp:  time.Now(),
+		Deadline:   q.Timeout,
+		Parameters: job.Params,
+		Env:        job.Env,
+	}
+
+	execCtx := ctx
+	if q.Timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, q.Timeout)
+		defer cancel()
+	}
+
+	result, err := q.Invoke(execCtx, job.Function, job.Event, fnCtx)
+	job.UpdatedAt = time.Now()
+
+	if err != nil {
+		job.Error = err.Error()
+		if job.Attempts > q.MaxRetries {
+			job.State = StateDead
+		} else {
+			job.State = StateQueued
+			job.NextAttempt = time.Now().Add(retryBackoff(job.Attempts))
+		}
+		if perr := q.Store.Put(job); perr != nil {
+			fmt.Printf("[async] failed to persist job %s after error: %v\n", job.ID, perr)
+		}
+		return
+	}
+
+	job.State = StateSucceeded
+	job.Result = result
+	job.Error = ""
+	if err := q.Store.Put(job); err != nil {
+		fmt.Printf("[async] failed to persist job %s result: %v\n", job.ID, err)
+	}
+}
+
+// retryBackoff grows exponentially with attempt count, capped at 5 minutes.
+func retryBackoff(attempt int) time.Duration {
+	const maxBackoff = 5 * time.Minute
+	d := time.Duration(1<<uint(attempt)) * time.Second
+	if d > maxBackoff || d <= 0 {
+		return maxBackoff
+	}
+	return d
+}
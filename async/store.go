@@ -0,0 +1,188 @@
+package async
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// JobState is the lifecycle state of an async job.
+type JobState string
+
+const (
+	StateQueued    JobState = "queued"
+	StateRunning   JobState = "running"
+	StateSucceeded JobState = "succeeded"
+	StateFailed    JobState = "failed"
+	StateDead      JobState = "dead"
+)
+
+// Job is a single async invocation, persisted so it survives a restart.
+type Job struct {
+	ID          string            `json:"id"`
+	Function    string            `json:"function"`
+	Event       []byte            `json:"event"`
+	Params      map[string]string `json:"params,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	State       JobState          `json:"state"`
+	Result      []byte            `json:"result,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Attempts    int               `json:"attempts"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	NextAttempt time.Time         `json:"next_attempt"`
+}
+
+var bucketJobs = []byte("jobs")
+
+// Store persists jobs to a BoltDB file so in-flight async work survives a
+// server restart.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens (creating if necessary) the queue database under dir.
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create queue dir: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "fnserve-queue.db"), 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketJobs)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize queue store: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put inserts or updates a job.
+func (s *Store) Put(job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", job.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketJobs).Put([]byte(job.ID), data)
+	})
+}
+
+// Get looks up a job by ID.
+func (s *Store) Get(id string) (Job, bool, error) {
+	var job Job
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketJobs).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &job)
+	})
+	return job, found, err
+}
+
+// List returns every job, optionally filtered to a single state. An empty
+// state returns all jobs.
+func (s *Store) List(state JobState) ([]Job, error) {
+	var jobs []Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketJobs).ForEach(func(_, v []byte) error {
+			var job Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if state == "" || job.State == state {
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+// ListReady returns queued jobs whose next retry attempt is due.
+func (s *Store) ListReady(now time.Time) ([]Job, error) {
+	queued, err := s.List(StateQueued)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := make([]Job, 0, len(queued))
+	for _, job := range queued {
+		if !job.NextAttempt.After(now) {
+			ready = append(ready, job)
+		}
+	}
+	return ready, nil
+}
+
+// RecoverRunning re-queues every job left in the running state, e.g. by a
+// server that crashed mid-execution. It should be called once at startup,
+// before the queue starts dispatching, so a restart never strands in-flight
+// work.
+func (s *Store) RecoverRunning() (int, error) {
+	running, err := s.List(StateRunning)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, job := range running {
+		job.State = StateQueued
+		job.NextAttempt = now
+		job.UpdatedAt = now
+		if err := s.Put(job); err != nil {
+			return 0, fmt.Errorf("failed to requeue job %s: %w", job.ID, err)
+		}
+	}
+	return len(running), nil
+}
+
+// Claim atomically transitions a queued job to running and bumps its
+// attempt count, so two dispatch ticks racing on the same job can't both
+// invoke it. ok is false if the job was no longer queued (already claimed,
+// or in a terminal state) by the time Claim ran.
+func (s *Store) Claim(id string) (job Job, ok bool, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketJobs)
+		data := b.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("job %q not found", id)
+		}
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		if job.State != StateQueued {
+			return nil
+		}
+
+		job.State = StateRunning
+		job.Attempts++
+		job.UpdatedAt = time.Now()
+		ok = true
+
+		updated, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(id), updated)
+	})
+	return job, ok, err
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/homecloudhq/fnserve/dev"
@@ -11,6 +14,10 @@ var (
 	devPort           int
 	devConcurrency    int
 	devRequestTimeout time.Duration
+	devRuntimeMode    string
+	devOTLPEndpoint   string
+	devWarmPool       bool
+	devWatchIgnore    string
 )
 
 var devCmd = &cobra.Command{
@@ -19,19 +26,46 @@ var devCmd = &cobra.Command{
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir := args[0]
+		if devRuntimeMode != "docker" && devRuntimeMode != "host" {
+			return fmt.Errorf("invalid --runtime %q: must be \"docker\" or \"host\"", devRuntimeMode)
+		}
+		otlpEndpoint := devOTLPEndpoint
+		if otlpEndpoint == "" {
+			otlpEndpoint = os.Getenv("FNSERVE_OTLP_ENDPOINT")
+		}
 		d := dev.DevServer{
-			Dir:         dir,
-			Port:        devPort,
-			Concurrency: devConcurrency,
-			Timeout:     devRequestTimeout,
+			Dir:          dir,
+			Port:         devPort,
+			Concurrency:  devConcurrency,
+			Timeout:      devRequestTimeout,
+			Runtime:      devRuntimeMode,
+			Warm:         devWarmPool,
+			OTLPEndpoint: otlpEndpoint,
+			WatchIgnore:  splitWatchIgnore(devWatchIgnore),
 		}
 		return d.Start()
 	},
 }
 
+// splitWatchIgnore parses a comma-separated --watch-ignore value into the
+// glob patterns dev.DevServer expects, dropping empty entries.
+func splitWatchIgnore(v string) []string {
+	var patterns []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
 func init() {
 	devCmd.Flags().IntVar(&devPort, "port", 8080, "Port to listen on")
 	devCmd.Flags().IntVar(&devConcurrency, "concurrency", 10, "Maximum number of concurrent function executions")
 	devCmd.Flags().DurationVar(&devRequestTimeout, "timeout", 30*time.Second, "Request timeout duration (e.g. 30s, 1m)")
+	devCmd.Flags().StringVar(&devRuntimeMode, "runtime", "docker", "Runtime to execute functions with (docker|host)")
+	devCmd.Flags().StringVar(&devOTLPEndpoint, "otlp-endpoint", "", "OTLP/gRPC collector endpoint for trace export (also read from FNSERVE_OTLP_ENDPOINT)")
+	devCmd.Flags().BoolVar(&devWarmPool, "warm", true, "Reuse long-lived warm worker processes instead of spawning one per request")
+	devCmd.Flags().StringVar(&devWatchIgnore, "watch-ignore", "", "Comma-separated glob patterns of directories to exclude from hot-reload watching (e.g. vendor,node_modules)")
 	rootCmd.AddCommand(devCmd)
 }
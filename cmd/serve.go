@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,6 +15,14 @@ var (
 	maxConcurrency int
 	requestTimeout time.Duration
 	workerPoolSize int
+	runtimeMode    string
+	otlpEndpoint   string
+	warmPool       bool
+	queueDir       string
+	maxRetries     int
+	asyncConc      int
+	tesWorkDir     string
+	asyncTimeout   time.Duration
 )
 
 var serveCmd = &cobra.Command{
@@ -22,6 +32,10 @@ var serveCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir := args[0]
 
+		if runtimeMode != "docker" && runtimeMode != "host" {
+			return fmt.Errorf("invalid --runtime %q: must be \"docker\" or \"host\"", runtimeMode)
+		}
+
 		// Create server with configuration
 		s := server.NewServer(dir)
 
@@ -29,6 +43,17 @@ var serveCmd = &cobra.Command{
 		s.Config.MaxConcurrentRequests = maxConcurrency
 		s.Config.RequestTimeout = requestTimeout
 		s.Config.WorkerPoolSize = workerPoolSize
+		s.Config.Runtime = runtimeMode
+		s.Config.Warm = warmPool
+		s.Config.OTLPEndpoint = otlpEndpoint
+		if s.Config.OTLPEndpoint == "" {
+			s.Config.OTLPEndpoint = os.Getenv("FNSERVE_OTLP_ENDPOINT")
+		}
+		s.Config.QueueDir = queueDir
+		s.Config.MaxRetries = maxRetries
+		s.Config.AsyncConcurrency = asyncConc
+		s.Config.TESWorkDir = tesWorkDir
+		s.Config.AsyncTimeout = asyncTimeout
 
 		return s.Start(port)
 	},
@@ -39,6 +64,14 @@ func init() {
 	serveCmd.Flags().IntVar(&maxConcurrency, "concurrency", 100, "Maximum number of concurrent function executions")
 	serveCmd.Flags().DurationVar(&requestTimeout, "timeout", 30*time.Second, "Request timeout duration (e.g. 30s, 1m)")
 	serveCmd.Flags().IntVar(&workerPoolSize, "workers", 10, "Size of the worker pool")
+	serveCmd.Flags().StringVar(&runtimeMode, "runtime", "docker", "Runtime to execute functions with (docker|host)")
+	serveCmd.Flags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/gRPC collector endpoint for trace export (also read from FNSERVE_OTLP_ENDPOINT)")
+	serveCmd.Flags().BoolVar(&warmPool, "warm", true, "Reuse long-lived warm worker processes instead of spawning one per request")
+	serveCmd.Flags().StringVar(&queueDir, "queue-dir", "", "Directory for the persistent async job queue (enables async invocation)")
+	serveCmd.Flags().IntVar(&maxRetries, "max-retries", 5, "Maximum retries for a failing async job before it's moved to the dead-letter state")
+	serveCmd.Flags().IntVar(&asyncConc, "async-concurrency", 0, "Maximum concurrent async job executions (defaults to --concurrency)")
+	serveCmd.Flags().StringVar(&tesWorkDir, "tes-work-dir", "", "Scratch directory for GA4GH TES task working directories (defaults to a temp dir; requires --queue-dir)")
+	serveCmd.Flags().DurationVar(&asyncTimeout, "async-timeout", 0, "Timeout for each async job invocation (defaults to --timeout)")
 
 	rootCmd.AddCommand(serveCmd)
 }
@@ -3,19 +3,45 @@ package dev
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/homecloudhq/fnserve/server"
 )
 
+// reloadExtensions are the file extensions that trigger a reload. Saving a
+// README or a fnserve.yaml manifest next to a function shouldn't restart
+// the server; only changes to a runtime's own source should.
+var reloadExtensions = map[string]bool{
+	".go": true,
+	".py": true,
+}
+
+// debounceWindow coalesces the burst of fsnotify events a single editor
+// save produces (write, then often a rename/chmod) into one reload.
+const debounceWindow = 300 * time.Millisecond
+
 type DevServer struct {
-	Dir         string
-	Port        int
-	Concurrency int
-	Timeout     time.Duration
+	Dir          string
+	Port         int
+	Concurrency  int
+	Timeout      time.Duration
+	Runtime      string
+	Warm         bool
+	OTLPEndpoint string
+
+	// WatchIgnore is a set of glob patterns, matched against both a watched
+	// directory's path relative to Dir and its base name, excluded from
+	// the file watcher (e.g. "vendor", "node_modules", "build").
+	WatchIgnore []string
 }
 
 func (d *DevServer) Start() error {
@@ -25,94 +51,152 @@ func (d *DevServer) Start() error {
 	}
 	defer watcher.Close()
 
-	done := make(chan bool)
-	serverCtx, serverCancel := context.WithCancel(context.Background())
-	defer serverCancel()
+	if err := d.watchRecursive(watcher, d.Dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", d.Dir, err)
+	}
 
-	// Track current server to allow for graceful restarts
-	var currentServer *http.Server
-	serverReady := make(chan bool, 1)
+	var mu sync.Mutex
+	var currentServer *server.Server
 
-	// Server start/restart function
+	// startServer shuts down the previous server (if any), waiting for its
+	// Shutdown to return, then starts a fresh one in a goroutine. It
+	// returns once that goroutine has started; it does not wait for the new
+	// server's listener to be bound.
 	startServer := func() {
-		// Cancel previous server if it exists
-		if currentServer != nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
+		mu.Lock()
+		prev := currentServer
+		mu.Unlock()
 
+		if prev != nil {
 			log.Println("Shutting down previous server...")
-			currentServer.Shutdown(ctx)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := prev.Shutdown(ctx); err != nil {
+				log.Println("Error shutting down previous server:", err)
+			}
+			cancel()
 		}
 
-		// Create new server with config
 		s := server.NewServer(d.Dir)
-
-		// Apply configuration
 		if d.Concurrency > 0 {
 			s.Config.MaxConcurrentRequests = d.Concurrency
 		}
 		if d.Timeout > 0 {
 			s.Config.RequestTimeout = d.Timeout
 		}
-
-		// Create HTTP server
-		currentServer = &http.Server{
-			Addr: fmt.Sprintf(":%d", d.Port),
-			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				// Custom handler that wraps the function server
-				s := server.NewServer(d.Dir)
-
-				// Start the server
-				s.Start(d.Port)
-			}),
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 30 * time.Second,
-			IdleTimeout:  60 * time.Second,
+		if d.Runtime != "" {
+			s.Config.Runtime = d.Runtime
 		}
+		s.Config.Warm = d.Warm
+		s.Config.OTLPEndpoint = d.OTLPEndpoint
+
+		mu.Lock()
+		currentServer = s
+		mu.Unlock()
 
+		ready := make(chan struct{})
 		go func() {
 			log.Printf("Starting development server on port %d\n", d.Port)
-			serverReady <- true
-			if err := s.Start(d.Port); err != nil && err != http.ErrServerClosed {
+			close(ready)
+			if err := s.Start(d.Port); err != nil {
 				log.Println("Server error:", err)
 			}
 		}()
+		<-ready
 	}
 
-	// Initial server start
-	go func() {
-		// Start the server
+	startServer()
+
+	pending := make(map[string]struct{})
+	var debounce *time.Timer
+	reload := func() {
+		mu.Lock()
+		files := make([]string, 0, len(pending))
+		for f := range pending {
+			files = append(files, f)
+		}
+		pending = make(map[string]struct{})
+		mu.Unlock()
+
+		sort.Strings(files)
+		log.Printf("[reload] %s changed, reloading server...\n", strings.Join(files, ", "))
 		startServer()
-		<-serverReady
-
-		// Watch for file changes
-		for {
-			select {
-			case <-serverCtx.Done():
-				return
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
-				}
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					fmt.Printf("[reload] %s modified, reloading server...\n", event.Name)
-					startServer()
-					<-serverReady
-				}
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := d.watchRecursive(watcher, event.Name); err != nil {
+						log.Println("Failed to watch new directory:", err)
+					}
+					continue
 				}
-				log.Println("Watcher error:", err)
 			}
+
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if !reloadExtensions[filepath.Ext(event.Name)] {
+				continue
+			}
+
+			mu.Lock()
+			pending[event.Name] = struct{}{}
+			mu.Unlock()
+
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.AfterFunc(debounceWindow, reload)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Println("Watcher error:", err)
 		}
-	}()
+	}
+}
 
-	err = watcher.Add(d.Dir)
+// watchRecursive adds dir and every subdirectory beneath it to watcher,
+// skipping any that match WatchIgnore. fsnotify only watches the
+// directories it's told about, not their descendants, so new directories
+// created later are picked up via the Create handling in Start.
+func (d *DevServer) watchRecursive(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != d.Dir && d.ignored(path) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// ignored reports whether path matches one of WatchIgnore's glob patterns,
+// tried against both its path relative to Dir and its base name.
+func (d *DevServer) ignored(path string) bool {
+	rel, err := filepath.Rel(d.Dir, path)
 	if err != nil {
-		return err
+		rel = path
 	}
-
-	<-done
-	return nil
+	base := filepath.Base(path)
+	for _, pattern := range d.WatchIgnore {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
 }
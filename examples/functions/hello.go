@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,22 +31,40 @@ type Response struct {
 	Timestamp  time.Time              `json:"timestamp"`
 }
 
+// frameRequest/frameResponse mirror the length-prefixed JSON wire format
+// used by runtime.WarmPool when FN_WARM=1.
+type frameRequest struct {
+	RequestID string          `json:"request_id"`
+	Event     json.RawMessage `json:"event"`
+	Context   *Context        `json:"context"`
+}
+
+type frameResponse struct {
+	RequestID string          `json:"request_id"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
 func main() {
-	// Read input from stdin
+	if os.Getenv("FN_WARM") == "1" {
+		if err := runWarmLoop(); err != nil {
+			fmt.Fprintf(os.Stderr, "warm loop error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	runOnce()
+}
+
+// runOnce handles a single invocation: event on stdin, context in
+// FN_CONTEXT, result on stdout. This is the one-shot fork-per-request path.
+func runOnce() {
 	body, err := io.ReadAll(os.Stdin)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Parse the event
-	var event Event
-	if err := json.Unmarshal(body, &event); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing event: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Get the context from environment
 	var ctx Context
 	if contextJSON := os.Getenv("FN_CONTEXT"); contextJSON != "" {
 		if err := json.Unmarshal([]byte(contextJSON), &ctx); err != nil {
@@ -53,27 +73,95 @@ func main() {
 		}
 	}
 
-	// Get name or use default
+	result, err := handle(body, &ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(result))
+}
+
+// runWarmLoop reads one length-prefixed JSON request frame at a time from
+// stdin and writes one response frame to stdout, until stdin is closed.
+func runWarmLoop() error {
+	stdin := bufio.NewReader(os.Stdin)
+	for {
+		req, err := readFrame(stdin)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read frame: %w", err)
+		}
+
+		resp := frameResponse{RequestID: req.RequestID}
+		if result, err := handle(req.Event, req.Context); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Result = result
+		}
+
+		if err := writeFrame(os.Stdout, resp); err != nil {
+			return fmt.Errorf("failed to write frame: %w", err)
+		}
+	}
+}
+
+// handle builds the hello-world response for a single event/context pair.
+func handle(eventJSON []byte, ctx *Context) (json.RawMessage, error) {
+	var event Event
+	if len(eventJSON) > 0 {
+		if err := json.Unmarshal(eventJSON, &event); err != nil {
+			return nil, fmt.Errorf("error parsing event: %w", err)
+		}
+	}
+
 	name := event.Name
 	if name == "" {
 		name = "World"
 	}
 
-	// Create the response
 	response := Response{
-		Message:    fmt.Sprintf("Hello, %s from Go!", name),
-		RequestID:  ctx.RequestID,
-		Timestamp:  time.Now(),
-		Parameters: ctx.Parameters,
-		TraceInfo:  ctx.Tracing,
+		Message:   fmt.Sprintf("Hello, %s from Go!", name),
+		Timestamp: time.Now(),
+	}
+	if ctx != nil {
+		response.RequestID = ctx.RequestID
+		response.Parameters = ctx.Parameters
+		response.TraceInfo = ctx.Tracing
 	}
 
-	// Output the response as JSON
-	output, err := json.Marshal(response)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating response: %v\n", err)
-		os.Exit(1)
+	return json.Marshal(response)
+}
+
+func readFrame(r *bufio.Reader) (frameRequest, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return frameRequest{}, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return frameRequest{}, err
 	}
+	var req frameRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return frameRequest{}, err
+	}
+	return req, nil
+}
 
-	fmt.Println(string(output))
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
 }
@@ -0,0 +1,210 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DockerConfig describes how a function should be packaged and run inside a
+// container. It is loaded from a fnserve.yaml or function.yaml manifest
+// placed alongside the function file.
+type DockerConfig struct {
+	Image   string            `yaml:"image"`
+	Command []string          `yaml:"command"`
+	Memory  string            `yaml:"memory"`
+	CPUs    string            `yaml:"cpus"`
+	Env     map[string]string `yaml:"env"`
+	Mounts  []string          `yaml:"mounts"`
+	Network string            `yaml:"network"`
+	// User is passed to `docker run -u` as "uid[:gid]". When set, a
+	// minimal /etc/passwd and /etc/group are generated and mounted so the
+	// container can resolve the UID without a matching entry in its own
+	// image.
+	User string `yaml:"user"`
+}
+
+// manifestSuffixes are tried in order next to the function file, both as a
+// per-function manifest (prefixed with the function's own base name, e.g.
+// "a.fnserve.yaml" for "a.go") and, failing that, as a manifest shared by
+// every function in the directory.
+var manifestSuffixes = []string{"fnserve.yaml", "function.yaml"}
+
+// LoadDockerConfig resolves functionPath's Docker manifest: it first looks
+// for a manifest named after the function itself ("<name>.fnserve.yaml" or
+// "<name>.function.yaml") next to functionPath, then falls back to a bare
+// "fnserve.yaml"/"function.yaml" shared by every function in the directory.
+// It returns (nil, "", nil) if no manifest is present, signalling that the
+// function should run through a host runtime instead of Docker. The second
+// return value is the resolved manifest's path, so callers can detect a
+// shared manifest being claimed by more than one function.
+func LoadDockerConfig(functionPath string) (*DockerConfig, string, error) {
+	dir := filepath.Dir(functionPath)
+	base := strings.TrimSuffix(filepath.Base(functionPath), filepath.Ext(functionPath))
+
+	var candidates []string
+	for _, suffix := range manifestSuffixes {
+		candidates = append(candidates, base+"."+suffix)
+	}
+	candidates = append(candidates, manifestSuffixes...)
+
+	for _, name := range candidates {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		var cfg DockerConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if cfg.Image == "" {
+			return nil, "", fmt.Errorf("%s: 'image' is required", name)
+		}
+		return &cfg, path, nil
+	}
+	return nil, "", nil
+}
+
+// DockerRuntime executes a function inside a Docker container using the
+// settings from DockerConfig, rather than as a host exec.Cmd.
+type DockerRuntime struct {
+	Config DockerConfig
+}
+
+func (r *DockerRuntime) Name() string { return "docker" }
+
+func (r *DockerRuntime) Execute(ctx context.Context, functionPath string, event []byte, fnCtx Context) ([]byte, error) {
+	ctxJSON, err := json.Marshal(fnCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal context: %w", err)
+	}
+
+	containerName := "fn-" + fnCtx.RequestID
+	args := []string{"run", "--rm", "-i", "--name", containerName}
+
+	if r.Config.Memory != "" {
+		args = append(args, "-m", r.Config.Memory)
+	}
+	if r.Config.CPUs != "" {
+		args = append(args, "--cpus", r.Config.CPUs)
+	}
+	if r.Config.Network != "" {
+		args = append(args, "--network", r.Config.Network)
+	}
+
+	if r.Config.User != "" {
+		passwdDir, err := writeMinimalPasswdGroup(r.Config.User)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare passwd/group for user %q: %w", r.Config.User, err)
+		}
+		defer os.RemoveAll(passwdDir)
+
+		args = append(args, "-u", r.Config.User,
+			"-v", filepath.Join(passwdDir, "passwd")+":/etc/passwd:ro",
+			"-v", filepath.Join(passwdDir, "group")+":/etc/group:ro",
+		)
+	}
+
+	for _, mount := range r.Config.Mounts {
+		args = append(args, "-v", mount)
+	}
+
+	args = append(args, "-e", fmt.Sprintf("FN_CONTEXT=%s", ctxJSON))
+	for k, v := range r.Config.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range fnCtx.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	args = append(args, r.Config.Image)
+	args = append(args, r.Config.Command...)
+
+	cmd := exec.Command("docker", args...)
+	cmd.Stdin = bytes.NewReader(event)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var timeout <-chan time.Time
+	if fnCtx.Deadline > 0 {
+		timeout = time.After(fnCtx.Deadline)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, fmt.Errorf("docker error: %s", out.String())
+		}
+	case <-timeout:
+		stopContainer(containerName)
+		<-done
+		return nil, fmt.Errorf("function execution timed out after %v", fnCtx.Deadline)
+	case <-ctx.Done():
+		stopContainer(containerName)
+		<-done
+		return nil, ctx.Err()
+	}
+
+	return out.Bytes(), nil
+}
+
+// stopContainer asks Docker to stop the container gracefully; `docker stop`
+// itself escalates to SIGKILL if it refuses to exit within the grace period.
+// `docker kill` is only tried when the `docker stop` command fails.
+func stopContainer(name string) {
+	const grace = 5 * time.Second
+	if err := exec.Command("docker", "stop", "-t", strconv.Itoa(int(grace.Seconds())), name).Run(); err != nil {
+		exec.Command("docker", "kill", name).Run()
+	}
+}
+
+// writeMinimalPasswdGroup writes a passwd/group pair to a temp directory so a
+// container can run as user (in "uid" or "uid:gid" form) without hitting
+// UID-not-found errors when the image has no matching entry.
+func writeMinimalPasswdGroup(user string) (string, error) {
+	uid, gid := user, user
+	if parts := strings.SplitN(user, ":", 2); len(parts) == 2 {
+		uid, gid = parts[0], parts[1]
+	}
+
+	dir, err := os.MkdirTemp("", "fnserve-docker-")
+	if err != nil {
+		return "", err
+	}
+
+	passwd := fmt.Sprintf("root:x:0:0:root:/root:/bin/sh\nfnuser:x:%s:%s:fnuser:/:/sbin/nologin\n", uid, gid)
+	group := fmt.Sprintf("root:x:0:\nfnuser:x:%s:\n", gid)
+
+	if err := os.WriteFile(filepath.Join(dir, "passwd"), []byte(passwd), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "group"), []byte(group), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
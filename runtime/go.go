@@ -12,6 +12,8 @@ import (
 
 type GoRuntime struct{}
 
+func (r *GoRuntime) Name() string { return "go" }
+
 func (r *GoRuntime) Execute(ctx context.Context, functionPath string, event []byte, fnCtx Context) ([]byte, error) {
 	// Create a temporary file for context
 	ctxJSON, err := json.Marshal(fnCtx)
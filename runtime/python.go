@@ -2,23 +2,58 @@ package runtime
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"time"
 )
 
 type PythonRuntime struct{}
 
-func (r *PythonRuntime) Execute(functionPath string, event []byte, ctx Context) ([]byte, error) {
-	// For now, just call python directly on the file
-	cmd := exec.Command("python3", functionPath)
+func (r *PythonRuntime) Name() string { return "python" }
+
+func (r *PythonRuntime) Execute(ctx context.Context, functionPath string, event []byte, fnCtx Context) ([]byte, error) {
+	ctxJSON, err := json.Marshal(fnCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal context: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "python3", functionPath)
 	cmd.Stdin = bytes.NewReader(event)
 
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, fmt.Sprintf("FN_CONTEXT=%s", ctxJSON))
+	for k, v := range fnCtx.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("python error: %s", out.String())
+	done := make(chan error)
+	go func() {
+		done <- cmd.Run()
+	}()
+
+	var timeout <-chan time.Time
+	if fnCtx.Deadline > 0 {
+		timeout = time.After(fnCtx.Deadline)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, fmt.Errorf("python error: %s", out.String())
+		}
+	case <-timeout:
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("function execution timed out after %v", fnCtx.Deadline)
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		return nil, ctx.Err()
 	}
 
 	return out.Bytes(), nil
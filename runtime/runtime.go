@@ -22,4 +22,7 @@ type TracingInfo struct {
 
 type Runtime interface {
 	Execute(ctx context.Context, functionPath string, event []byte, fnCtx Context) ([]byte, error)
+
+	// Name identifies the runtime for metrics and logging, e.g. "go", "python", "docker".
+	Name() string
 }
@@ -0,0 +1,280 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	// fastCrashWindow and maxFastCrashes bound how many times a warm
+	// worker is allowed to crash in quick succession before its pool is
+	// marked fatal, mirroring the circuit-breaker behavior of standard
+	// process-manager supervisors.
+	fastCrashWindow    = 10 * time.Second
+	maxFastCrashes     = 5
+	restartBackoffUnit = 200 * time.Millisecond
+)
+
+// workerRequest is the frame written to a warm worker's stdin.
+type workerRequest struct {
+	RequestID string          `json:"request_id"`
+	Event     json.RawMessage `json:"event"`
+	Context   *Context        `json:"context"`
+}
+
+// workerResponse is the frame read back from a warm worker's stdout.
+type workerResponse struct {
+	RequestID string          `json:"request_id"`
+	Result    json.RawMessage `json:"result"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// functionError wraps an error the function itself reported in a
+// well-formed response, as opposed to a failure to talk to the worker at
+// all. Execute propagates it without treating the worker as crashed.
+type functionError struct {
+	err error
+}
+
+func (e *functionError) Error() string { return e.err.Error() }
+func (e *functionError) Unwrap() error { return e.err }
+
+// WarmPool executes a function by keeping a small pool of long-lived child
+// processes running, each speaking a length-prefixed JSON-framed protocol
+// over stdin/stdout, instead of spawning a fresh process per request.
+type WarmPool struct {
+	FunctionPath string
+	Size         int
+
+	mu                     sync.Mutex
+	idle                   chan *warmWorker
+	lastCrash              time.Time
+	consecutiveFastCrashes int
+	fatal                  bool
+}
+
+// NewWarmPool creates a pool for functionPath with the given number of
+// prewarmed workers. Call Start before the first Execute.
+func NewWarmPool(functionPath string, size int) *WarmPool {
+	if size <= 0 {
+		size = 1
+	}
+	return &WarmPool{FunctionPath: functionPath, Size: size}
+}
+
+func (p *WarmPool) Name() string { return "warm" }
+
+// Start prewarms Size workers, returning an error if any of them fail to
+// launch.
+func (p *WarmPool) Start() error {
+	p.idle = make(chan *warmWorker, p.Size)
+	for i := 0; i < p.Size; i++ {
+		w, err := startWarmWorker(p.FunctionPath)
+		if err != nil {
+			return fmt.Errorf("failed to start warm worker %d for %s: %w", i, p.FunctionPath, err)
+		}
+		p.idle <- w
+	}
+	return nil
+}
+
+// Close kills every idle worker in the pool. In-flight workers checked out
+// via Execute are left to the caller's context cancellation.
+func (p *WarmPool) Close() {
+	for {
+		select {
+		case w := <-p.idle:
+			w.kill()
+		default:
+			return
+		}
+	}
+}
+
+func (p *WarmPool) Execute(ctx context.Context, functionPath string, event []byte, fnCtx Context) ([]byte, error) {
+	if p.isFatal() {
+		return nil, fmt.Errorf("warm pool for %s is marked fatal after repeated worker crashes", p.FunctionPath)
+	}
+
+	var w *warmWorker
+	select {
+	case w = <-p.idle:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	result, err := p.exchange(ctx, w, event, fnCtx)
+	if err != nil {
+		var ferr *functionError
+		if errors.As(err, &ferr) {
+			// The worker itself is fine; it's the function that errored.
+			// Return it to the pool instead of killing it.
+			p.idle <- w
+			return nil, ferr.err
+		}
+		p.replace(w)
+		return nil, err
+	}
+
+	p.idle <- w
+	return result, nil
+}
+
+func (p *WarmPool) exchange(ctx context.Context, w *warmWorker, event []byte, fnCtx Context) ([]byte, error) {
+	req := workerRequest{
+		RequestID: fnCtx.RequestID,
+		Event:     json.RawMessage(event),
+		Context:   &fnCtx,
+	}
+	if err := writeFrame(w.stdin, req); err != nil {
+		return nil, fmt.Errorf("failed to write frame to warm worker: %w", err)
+	}
+
+	type readResult struct {
+		resp workerResponse
+		err  error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		var resp workerResponse
+		err := readFrame(w.stdout, &resp)
+		done <- readResult{resp, err}
+	}()
+
+	var timeout <-chan time.Time
+	if fnCtx.Deadline > 0 {
+		timeout = time.After(fnCtx.Deadline)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, fmt.Errorf("warm worker framing error: %w", r.err)
+		}
+		if r.resp.RequestID != fnCtx.RequestID {
+			return nil, fmt.Errorf("warm worker frame mismatch: expected request %s, got %s", fnCtx.RequestID, r.resp.RequestID)
+		}
+		if r.resp.Error != "" {
+			return nil, &functionError{err: fmt.Errorf("function error: %s", r.resp.Error)}
+		}
+		return r.resp.Result, nil
+	case <-timeout:
+		return nil, fmt.Errorf("warm worker timed out after %v", fnCtx.Deadline)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// replace kills a worker that failed an exchange and starts its
+// replacement, tracking consecutive fast crashes and backing off between
+// restarts. After too many fast crashes in a row, the pool is marked
+// fatal and stops accepting new work.
+func (p *WarmPool) replace(w *warmWorker) {
+	w.kill()
+
+	p.mu.Lock()
+	now := time.Now()
+	if now.Sub(p.lastCrash) < fastCrashWindow {
+		p.consecutiveFastCrashes++
+	} else {
+		p.consecutiveFastCrashes = 1
+	}
+	p.lastCrash = now
+	crashes := p.consecutiveFastCrashes
+	p.mu.Unlock()
+
+	if crashes >= maxFastCrashes {
+		p.mu.Lock()
+		p.fatal = true
+		p.mu.Unlock()
+		fmt.Printf("[warmpool] %s marked fatal after %d crashes within %v\n", p.FunctionPath, crashes, fastCrashWindow)
+		return
+	}
+
+	time.Sleep(time.Duration(crashes) * restartBackoffUnit)
+
+	nw, err := startWarmWorker(p.FunctionPath)
+	if err != nil {
+		fmt.Printf("[warmpool] failed to restart worker for %s: %v\n", p.FunctionPath, err)
+		return
+	}
+	p.idle <- nw
+}
+
+func (p *WarmPool) isFatal() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fatal
+}
+
+// warmWorker is a single long-lived child process speaking the framed
+// protocol over its stdin/stdout.
+type warmWorker struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func startWarmWorker(functionPath string) (*warmWorker, error) {
+	cmd := exec.Command(functionPath)
+	cmd.Env = append(os.Environ(), "FN_WARM=1")
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start warm worker process: %w", err)
+	}
+
+	return &warmWorker{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+func (w *warmWorker) kill() {
+	w.stdin.Close()
+	if w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	w.cmd.Wait()
+}
+
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readFrame(r *bufio.Reader, v interface{}) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
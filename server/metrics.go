@@ -0,0 +1,61 @@
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metrics holds the Prometheus collectors used to instrument function
+// invocations. Each Server owns its own registry so multiple servers can run
+// in the same process without collector name collisions.
+type metrics struct {
+	registry *prometheus.Registry
+
+	requestsTotal        *prometheus.CounterVec
+	requestDuration      *prometheus.HistogramVec
+	functionExecDuration *prometheus.HistogramVec
+	activeRequests       prometheus.Gauge
+	semaphoreSaturation  prometheus.Gauge
+	runtimeErrorsTotal   *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	registry := prometheus.NewRegistry()
+	factory := promauto.With(registry)
+
+	return &metrics{
+		registry: registry,
+
+		requestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "fnserve_requests_total",
+			Help: "Total number of function invocations, by function and status.",
+		}, []string{"function", "status"}),
+
+		requestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fnserve_request_duration_seconds",
+			Help:    "End-to-end HTTP request duration, by function.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"function"}),
+
+		functionExecDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fnserve_function_exec_duration_seconds",
+			Help:    "Time spent inside the runtime executing the function, by function.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"function"}),
+
+		activeRequests: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "fnserve_active_requests",
+			Help: "Number of requests currently being handled.",
+		}),
+
+		semaphoreSaturation: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "fnserve_semaphore_saturation",
+			Help: "Fraction of the concurrency semaphore currently checked out (0-1).",
+		}),
+
+		runtimeErrorsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "fnserve_runtime_errors_total",
+			Help: "Total number of runtime execution errors, by function and runtime.",
+		}, []string{"function", "runtime"}),
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,13 +13,66 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/homecloudhq/fnserve/async"
 	"github.com/homecloudhq/fnserve/runtime"
+	"github.com/homecloudhq/fnserve/tes"
 )
 
 type ServerConfig struct {
 	MaxConcurrentRequests int
 	RequestTimeout        time.Duration
 	WorkerPoolSize        int
+
+	// Runtime selects how functions are executed: "docker" (default) routes
+	// any function whose directory has a fnserve.yaml/function.yaml
+	// manifest through the Docker runtime, falling back to the host
+	// runtimes otherwise; "host" always uses the host runtimes.
+	Runtime string
+
+	// OTLPEndpoint is the OTLP/gRPC collector address (e.g.
+	// "localhost:4317") that request spans are exported to. Tracing is
+	// disabled when empty.
+	OTLPEndpoint string
+
+	// Warm enables the warm worker pool: WorkerPoolSize long-lived
+	// processes per function, speaking a framed protocol over
+	// stdin/stdout, instead of spawning a fresh process per request.
+	// Functions that can't run a request loop (e.g. plain Python scripts)
+	// always fall back to their one-shot runtime regardless of this flag.
+	Warm bool
+
+	// QueueDir is the directory holding the BoltDB-backed async job queue.
+	// Async invocation (POST /{name}?async=1 or /async/{name}, GET
+	// /jobs/{job_id}) is disabled when empty.
+	QueueDir string
+
+	// AsyncConcurrency bounds how many async jobs run at once,
+	// independent of MaxConcurrentRequests. Defaults to
+	// MaxConcurrentRequests when zero.
+	AsyncConcurrency int
+
+	// MaxRetries bounds how many times a failing async job is retried
+	// before it's moved to the dead-letter state.
+	MaxRetries int
+
+	// AsyncTimeout bounds each async job invocation, mirroring
+	// RequestTimeout on the sync path. Defaults to RequestTimeout when
+	// zero.
+	AsyncTimeout time.Duration
+
+	// TESWorkDir is the scratch root TES tasks materialize their inputs
+	// and run their executors in; each task gets TESWorkDir/<task-id>.
+	// Defaults to a temp directory when empty. The GA4GH TES v1 API
+	// (/ga4gh/tes/v1/...) is only exposed when QueueDir is set, since
+	// tasks are persisted alongside the async job store.
+	TESWorkDir string
 }
 
 type Server struct {
@@ -28,16 +82,23 @@ type Server struct {
 	// For concurrency control
 	semaphore    chan struct{}
 	functionPool sync.Pool
-	stats        Stats
-}
 
-type Stats struct {
-	sync.Mutex
-	ActiveRequests   int
-	TotalRequests    int64
-	SuccessRequests  int64
-	FailedRequests   int64
-	TotalExecutionMs int64
+	metrics        *metrics
+	tracerShutdown func(context.Context) error
+
+	asyncStore *async.Store
+	asyncQueue *async.Queue
+
+	// warmPools holds every warm pool started in Start, so Shutdown (and
+	// Start's own return, for any reason) can stop their child processes
+	// instead of abandoning them — this matters most for dev's hot-reload,
+	// which restarts the server on every save.
+	warmPools []*runtime.WarmPool
+
+	tesStore   *tes.Store
+	tesManager *tes.Manager
+
+	httpServer *http.Server
 }
 
 // NewServer creates a new server with default configuration
@@ -48,14 +109,27 @@ func NewServer(dir string) *Server {
 			MaxConcurrentRequests: 100,
 			RequestTimeout:        30 * time.Second,
 			WorkerPoolSize:        10,
+			Runtime:               "docker",
+			Warm:                  true,
+			MaxRetries:            5,
 		},
-		stats: Stats{},
+		metrics: newMetrics(),
 	}
 }
 
 func (s *Server) Start(port int) error {
 	// Initialize concurrency control
 	s.semaphore = make(chan struct{}, s.Config.MaxConcurrentRequests)
+	if s.metrics == nil {
+		s.metrics = newMetrics()
+	}
+
+	tracerShutdown, err := initTracer(context.Background(), s.Config.OTLPEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	s.tracerShutdown = tracerShutdown
+	defer s.tracerShutdown(context.Background())
 
 	mux := http.NewServeMux()
 
@@ -65,9 +139,26 @@ func (s *Server) Start(port int) error {
 		return fmt.Errorf("failed to read dir: %w", err)
 	}
 
-	// Register health check and stats endpoint
+	// Register health, metrics and stats endpoints
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
 	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/jobs", s.handleListJobs)
+	mux.HandleFunc("/jobs/", s.handleGetJob)
+
+	// functions maps a registered function name to how it's invoked, so
+	// the async queue can dispatch jobs through the same runtimes as sync
+	// requests.
+	functions := make(map[string]registeredFunction)
+
+	// manifestOwner tracks which function first claimed each resolved
+	// Docker manifest path, so a bare fnserve.yaml/function.yaml shared by
+	// more than one function is rejected instead of silently routing them
+	// to the same image (per-function "<name>.fnserve.yaml" manifests
+	// never collide here since each is unique to its function).
+	manifestOwner := make(map[string]string)
+
+	defer s.closeWarmPools()
 
 	// Register function endpoints
 	for _, f := range files {
@@ -77,8 +168,60 @@ func (s *Server) Start(port int) error {
 		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
 		functionPath := filepath.Join(s.Dir, f.Name())
 
+		// Docker manifests ("fnserve.yaml", "<name>.function.yaml", ...)
+		// configure the functions beside them and are not functions
+		// themselves.
+		if strings.HasSuffix(f.Name(), "fnserve.yaml") || strings.HasSuffix(f.Name(), "function.yaml") {
+			continue
+		}
+
+		// Choose runtime for this function. A fnserve.yaml/function.yaml
+		// manifest next to the function routes it through the Docker
+		// runtime unless the server was configured to force the host
+		// runtimes.
+		var rt runtime.Runtime
+		dockerCfg, manifestPath, err := runtime.LoadDockerConfig(functionPath)
+		if err != nil {
+			return fmt.Errorf("failed to load docker config for %s: %w", name, err)
+		}
+		if dockerCfg != nil {
+			if owner, claimed := manifestOwner[manifestPath]; claimed {
+				return fmt.Errorf("functions %q and %q both resolve to manifest %s; add a per-function <name>.fnserve.yaml for each instead of sharing one", owner, name, manifestPath)
+			}
+			manifestOwner[manifestPath] = name
+		}
+		switch {
+		case dockerCfg != nil && s.Config.Runtime != "host":
+			rt = &runtime.DockerRuntime{Config: *dockerCfg}
+		case strings.HasSuffix(functionPath, ".py"):
+			// Plain scripts can't run the warm-pool frame loop, so they
+			// always use the one-shot runtime.
+			rt = &runtime.PythonRuntime{}
+		case s.Config.Warm:
+			pool := runtime.NewWarmPool(functionPath, s.Config.WorkerPoolSize)
+			if err := pool.Start(); err != nil {
+				return fmt.Errorf("failed to prewarm workers for %s: %w", name, err)
+			}
+			s.warmPools = append(s.warmPools, pool)
+			rt = pool
+		default:
+			rt = &runtime.GoRuntime{}
+		}
+
+		functions[name] = registeredFunction{path: functionPath, rt: rt}
+
+		// Register the dedicated async endpoint for this function.
+		mux.HandleFunc("/async/"+name, func(w http.ResponseWriter, r *http.Request) {
+			s.handleAsyncEnqueue(w, r, name)
+		})
+
 		// Register endpoint
 		mux.HandleFunc("/"+name, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("async") == "1" {
+				s.handleAsyncEnqueue(w, r, name)
+				return
+			}
+
 			// Concurrency control - acquire semaphore or reject if too many requests
 			select {
 			case s.semaphore <- struct{}{}:
@@ -92,27 +235,41 @@ func (s *Server) Start(port int) error {
 				return
 			}
 
+			s.metrics.activeRequests.Inc()
+			defer s.metrics.activeRequests.Dec()
+			s.metrics.semaphoreSaturation.Set(float64(len(s.semaphore)) / float64(cap(s.semaphore)))
+			// Refresh the gauge on release too. This defer runs before the
+			// semaphore slot acquired above is given back, hence the -1.
+			defer func() {
+				s.metrics.semaphoreSaturation.Set(float64(len(s.semaphore)-1) / float64(cap(s.semaphore)))
+			}()
+
 			start := time.Now()
 			reqID := uuid.NewString()
-			traceID := r.Header.Get("X-Trace-ID")
-			if traceID == "" {
-				traceID = uuid.NewString()
-			}
 
-			// Update stats
-			s.stats.Lock()
-			s.stats.ActiveRequests++
-			s.stats.TotalRequests++
-			s.stats.Unlock()
+			// Start a span for the request, linking to the caller's span via
+			// W3C traceparent (or fnserve's legacy X-Trace-ID/X-Parent-Span
+			// headers when no traceparent is present).
+			tracer := otel.Tracer(tracerName)
+			spanCtx, span := tracer.Start(s.extractParentContext(r), "fnserve."+name,
+				trace.WithAttributes(
+					attribute.String("fn.name", name),
+					attribute.String("fn.request_id", reqID),
+				),
+			)
+			defer span.End()
+			sc := span.SpanContext()
 
 			// Create context with timeout
-			ctx, cancel := context.WithTimeout(r.Context(), s.Config.RequestTimeout)
+			ctx, cancel := context.WithTimeout(spanCtx, s.Config.RequestTimeout)
 			defer cancel()
 
 			body, err := io.ReadAll(r.Body)
 			if err != nil {
 				http.Error(w, `{"error":"invalid request body"}`, 400)
-				s.recordFailure(start)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				s.recordResult(name, "error", start)
 				return
 			}
 
@@ -139,44 +296,114 @@ func (s *Server) Start(port int) error {
 				Parameters: params,
 				Env:        env,
 				Tracing: runtime.TracingInfo{
-					TraceID:  traceID,
-					SpanID:   uuid.NewString(),
+					TraceID:  sc.TraceID().String(),
+					SpanID:   sc.SpanID().String(),
 					ParentID: r.Header.Get("X-Parent-Span"),
 				},
 			}
 
-			// Choose runtime
-			var rt runtime.Runtime
-			if strings.HasSuffix(functionPath, ".py") {
-				rt = &runtime.PythonRuntime{}
-			} else {
-				rt = &runtime.GoRuntime{}
-			}
+			// Execute function with context, wrapped in its own child span
+			execCtx, execSpan := tracer.Start(ctx, "fnserve.execute",
+				trace.WithAttributes(
+					attribute.String("fn.name", name),
+					attribute.String("fn.runtime", rt.Name()),
+				),
+			)
+			execStart := time.Now()
+			result, err := rt.Execute(execCtx, functionPath, body, fnCtx)
+			s.metrics.functionExecDuration.WithLabelValues(name).Observe(time.Since(execStart).Seconds())
+			execSpan.End()
 
-			// Execute function with context
-			result, err := rt.Execute(ctx, functionPath, body, fnCtx)
 			if err != nil {
 				w.WriteHeader(500)
 				w.Header().Set("Content-Type", "application/json")
 				fmt.Fprintf(w, `{"error": %q}`, err.Error())
-				s.recordFailure(start)
+				s.metrics.runtimeErrorsTotal.WithLabelValues(name, rt.Name()).Inc()
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				s.recordResult(name, "error", start)
 				return
 			}
 
+			span.SetAttributes(
+				attribute.Int("fn.bytes_in", len(body)),
+				attribute.Int("fn.bytes_out", len(result)),
+			)
+			span.SetStatus(codes.Ok, "")
+
 			w.Header().Set("Content-Type", "application/json")
 			w.Header().Set("X-Request-ID", reqID)
-			w.Header().Set("X-Trace-ID", traceID)
+			w.Header().Set("X-Trace-ID", sc.TraceID().String())
 			w.Write(result)
 
 			duration := time.Since(start)
-			s.recordSuccess(start)
+			s.recordResult(name, "success", start)
 
 			fmt.Printf("[req=%s] %s %s (%s)\n", reqID, r.Method, r.URL.Path, duration)
 		})
 	}
 
+	// Start the async queue when a queue directory was configured. It
+	// dispatches through the same runtimes registered above, at a
+	// concurrency independent of the sync semaphore.
+	if s.Config.QueueDir != "" {
+		store, err := async.OpenStore(s.Config.QueueDir)
+		if err != nil {
+			return fmt.Errorf("failed to open async queue: %w", err)
+		}
+		s.asyncStore = store
+		defer store.Close()
+
+		// A previous crash can leave jobs stuck "running"; put them back
+		// on the queue so a restart doesn't strand in-flight work.
+		if n, err := store.RecoverRunning(); err != nil {
+			return fmt.Errorf("failed to recover in-flight async jobs: %w", err)
+		} else if n > 0 {
+			fmt.Printf("[async] requeued %d in-flight job(s) left running by a previous crash\n", n)
+		}
+
+		concurrency := s.Config.AsyncConcurrency
+		if concurrency <= 0 {
+			concurrency = s.Config.MaxConcurrentRequests
+		}
+
+		asyncTimeout := s.Config.AsyncTimeout
+		if asyncTimeout <= 0 {
+			asyncTimeout = s.Config.RequestTimeout
+		}
+
+		s.asyncQueue = async.NewQueue(store, func(ctx context.Context, function string, event []byte, fnCtx runtime.Context) ([]byte, error) {
+			fn, ok := functions[function]
+			if !ok {
+				return nil, fmt.Errorf("unknown function %q", function)
+			}
+			return fn.rt.Execute(ctx, fn.path, event, fnCtx)
+		}, concurrency, s.Config.MaxRetries, asyncTimeout)
+
+		queueCtx, queueCancel := context.WithCancel(context.Background())
+		defer queueCancel()
+		go s.asyncQueue.Run(queueCtx)
+
+		// The GA4GH TES v1 API persists tasks alongside the async job
+		// queue, so it rides on the same --queue-dir.
+		tesStore, err := tes.OpenStore(s.Config.QueueDir)
+		if err != nil {
+			return fmt.Errorf("failed to open tes store: %w", err)
+		}
+		s.tesStore = tesStore
+		defer tesStore.Close()
+
+		tesWorkDir := s.Config.TESWorkDir
+		if tesWorkDir == "" {
+			tesWorkDir = filepath.Join(os.TempDir(), "fnserve-tes")
+		}
+		s.tesManager = tes.NewManager(tesStore, tesWorkDir, s.Config.Runtime != "host")
+
+		s.registerTESRoutes(mux)
+	}
+
 	// Create server with timeouts
-	server := &http.Server{
+	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      mux,
 		ReadTimeout:  30 * time.Second,
@@ -185,8 +412,38 @@ func (s *Server) Start(port int) error {
 	}
 
 	fmt.Printf("FnServe listening on %s (max concurrent: %d)\n",
-		server.Addr, s.Config.MaxConcurrentRequests)
-	return server.ListenAndServe()
+		s.httpServer.Addr, s.Config.MaxConcurrentRequests)
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// closeWarmPools stops every warm pool's idle workers. It runs as Start
+// returns, regardless of why. Workers checked out by a request still in
+// flight at that moment are not stopped; they go back to their pool after
+// Close has run.
+func (s *Server) closeWarmPools() {
+	for _, pool := range s.warmPools {
+		pool.Close()
+	}
+}
+
+// Shutdown gracefully stops the server's HTTP listener, causing Start to
+// return (and run its deferred cleanup: tracer shutdown, async queue, TES
+// store, and warm pools). It is a no-op if the server was never started.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+// registeredFunction is how the async queue invokes a function discovered
+// at Start() time, using the same runtime as its sync endpoint.
+type registeredFunction struct {
+	path string
+	rt   runtime.Runtime
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -194,9 +451,142 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"status":"ok"}`))
 }
 
+// handleAsyncEnqueue persists a job for name and returns its job ID,
+// backing both POST /{name}?async=1 and POST /async/{name}.
+func (s *Server) handleAsyncEnqueue(w http.ResponseWriter, r *http.Request, name string) {
+	if s.asyncQueue == nil {
+		http.Error(w, `{"error":"async queue not configured; start with --queue-dir"}`, http.StatusNotImplemented)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, 400)
+		return
+	}
+
+	params := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if key == "async" || len(values) == 0 {
+			continue
+		}
+		params[key] = values[0]
+	}
+
+	env := make(map[string]string)
+	for _, header := range []string{"X-API-Key", "Authorization", "X-Forwarded-For"} {
+		if val := r.Header.Get(header); val != "" {
+			env[header] = val
+		}
+	}
+
+	job, err := s.asyncQueue.Enqueue(name, body, params, env)
+	if err != nil {
+		http.Error(w, `{"error":"failed to enqueue job"}`, 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, `{"job_id": %q}`, job.ID)
+}
+
+// handleGetJob serves GET /jobs/{job_id}.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	if s.asyncStore == nil {
+		http.Error(w, `{"error":"async queue not configured; start with --queue-dir"}`, http.StatusNotImplemented)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if id == "" {
+		http.Error(w, `{"error":"job id required"}`, 400)
+		return
+	}
+
+	job, found, err := s.asyncStore.Get(id)
+	if err != nil {
+		http.Error(w, `{"error":"failed to read job"}`, 500)
+		return
+	}
+	if !found {
+		http.Error(w, `{"error":"job not found"}`, 404)
+		return
+	}
+
+	writeJSON(w, job)
+}
+
+// handleListJobs serves GET /jobs, optionally filtered by ?state=.
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	if s.asyncStore == nil {
+		http.Error(w, `{"error":"async queue not configured; start with --queue-dir"}`, http.StatusNotImplemented)
+		return
+	}
+
+	jobs, err := s.asyncStore.List(async.JobState(r.URL.Query().Get("state")))
+	if err != nil {
+		http.Error(w, `{"error":"failed to list jobs"}`, 500)
+		return
+	}
+
+	writeJSON(w, jobs)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Println("failed to encode response:", err)
+	}
+}
+
+// handleStats is a thin JSON view over the same Prometheus collectors that
+// back /metrics, kept so existing callers of /stats don't break.
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
-	s.stats.Lock()
-	defer s.stats.Unlock()
+	families, err := s.metrics.registry.Gather()
+	if err != nil {
+		http.Error(w, `{"error":"failed to gather metrics"}`, 500)
+		return
+	}
+
+	var totalRequests, successRequests, failedRequests int64
+	var activeRequests, totalExecSeconds, execSampleCount float64
+
+	for _, mf := range families {
+		switch mf.GetName() {
+		case "fnserve_requests_total":
+			for _, m := range mf.GetMetric() {
+				count := int64(m.GetCounter().GetValue())
+				totalRequests += count
+				for _, label := range m.GetLabel() {
+					if label.GetName() != "status" {
+						continue
+					}
+					switch label.GetValue() {
+					case "success":
+						successRequests += count
+					case "error":
+						failedRequests += count
+					}
+				}
+			}
+		case "fnserve_active_requests":
+			for _, m := range mf.GetMetric() {
+				activeRequests += m.GetGauge().GetValue()
+			}
+		case "fnserve_function_exec_duration_seconds":
+			for _, m := range mf.GetMetric() {
+				h := m.GetHistogram()
+				totalExecSeconds += h.GetSampleSum()
+				execSampleCount += float64(h.GetSampleCount())
+			}
+		}
+	}
+
+	var avgExecutionMs int64
+	if execSampleCount > 0 {
+		avgExecutionMs = int64((totalExecSeconds / execSampleCount) * 1000)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	fmt.Fprintf(w, `{
@@ -206,40 +596,44 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 		"failed_requests": %d,
 		"avg_execution_ms": %d
 	}`,
-		s.stats.ActiveRequests,
-		s.stats.TotalRequests,
-		s.stats.SuccessRequests,
-		s.stats.FailedRequests,
-		s.calcAvgExecutionTime(),
+		int64(activeRequests),
+		totalRequests,
+		successRequests,
+		failedRequests,
+		avgExecutionMs,
 	)
 }
 
-func (s *Server) recordSuccess(startTime time.Time) {
-	duration := time.Since(startTime)
-
-	s.stats.Lock()
-	defer s.stats.Unlock()
-
-	s.stats.ActiveRequests--
-	s.stats.SuccessRequests++
-	s.stats.TotalExecutionMs += duration.Milliseconds()
+// recordResult records the outcome of a request against the Prometheus
+// collectors shared by /metrics and /stats.
+func (s *Server) recordResult(function, status string, start time.Time) {
+	s.metrics.requestsTotal.WithLabelValues(function, status).Inc()
+	s.metrics.requestDuration.WithLabelValues(function).Observe(time.Since(start).Seconds())
 }
 
-func (s *Server) recordFailure(startTime time.Time) {
-	duration := time.Since(startTime)
-
-	s.stats.Lock()
-	defer s.stats.Unlock()
+// extractParentContext resolves the parent span context for an incoming
+// request, preferring a standard W3C traceparent header and falling back to
+// fnserve's legacy X-Trace-ID/X-Parent-Span headers.
+func (s *Server) extractParentContext(r *http.Request) context.Context {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
 
-	s.stats.ActiveRequests--
-	s.stats.FailedRequests++
-	s.stats.TotalExecutionMs += duration.Milliseconds()
-}
+	traceID := r.Header.Get("X-Trace-ID")
+	parentSpanID := r.Header.Get("X-Parent-Span")
+	if traceID == "" && parentSpanID == "" {
+		return ctx
+	}
 
-func (s *Server) calcAvgExecutionTime() int64 {
-	total := s.stats.SuccessRequests + s.stats.FailedRequests
-	if total == 0 {
-		return 0
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    deriveTraceID(traceID),
+		SpanID:     deriveSpanID(parentSpanID),
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	if !sc.IsValid() {
+		return ctx
 	}
-	return s.stats.TotalExecutionMs / total
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
 }
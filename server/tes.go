@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/homecloudhq/fnserve/tes"
+)
+
+const tesBasePath = "/ga4gh/tes/v1/"
+
+// registerTESRoutes wires up the GA4GH TES v1 endpoints. It is only called
+// when the async queue is enabled (--queue-dir), since tasks are persisted
+// alongside the async job store.
+func (s *Server) registerTESRoutes(mux *http.ServeMux) {
+	mux.HandleFunc(tesBasePath+"service-info", s.handleTESServiceInfo)
+	mux.HandleFunc(tesBasePath+"tasks", s.handleTESTasks)
+	mux.HandleFunc(tesBasePath+"tasks/", s.handleTESTask)
+}
+
+func (s *Server) handleTESServiceInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, tes.ServiceInfo{
+		ID:   "org.homecloudhq.fnserve",
+		Name: "fnserve",
+		Doc:  "fnserve functions exposed as GA4GH TES v1 tasks",
+		Type: tes.Type{
+			Group:    "org.ga4gh",
+			Artifact: "tes",
+			Version:  "1.1.0",
+		},
+	})
+}
+
+// handleTESTasks serves POST /tasks (create) and GET /tasks (list).
+func (s *Server) handleTESTasks(w http.ResponseWriter, r *http.Request) {
+	if s.tesManager == nil {
+		http.Error(w, `{"error":"TES API not configured; start with --queue-dir"}`, http.StatusNotImplemented)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.handleTESCreateTask(w, r)
+	case http.MethodGet:
+		s.handleTESListTasks(w, r)
+	default:
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleTESCreateTask(w http.ResponseWriter, r *http.Request) {
+	var task tes.Task
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		http.Error(w, `{"error":"invalid task body"}`, http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.tesManager.CreateTask(task)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	writeJSON(w, map[string]string{"id": id})
+}
+
+func (s *Server) handleTESListTasks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	pageSize := 0
+	if v := q.Get("page_size"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			pageSize = n
+		}
+	}
+
+	tasks, nextToken, err := s.tesStore.List(q.Get("name_prefix"), pageSize, q.Get("page_token"))
+	if err != nil {
+		http.Error(w, `{"error":"failed to list tasks"}`, http.StatusInternalServerError)
+		return
+	}
+
+	view := tesView(q.Get("view"))
+	for i := range tasks {
+		tasks[i] = applyTESView(tasks[i], view)
+	}
+
+	writeJSON(w, tes.ListTasksResponse{Tasks: tasks, NextPageToken: nextToken})
+}
+
+// handleTESTask serves GET /tasks/{id} and POST /tasks/{id}:cancel.
+func (s *Server) handleTESTask(w http.ResponseWriter, r *http.Request) {
+	if s.tesManager == nil {
+		http.Error(w, `{"error":"TES API not configured; start with --queue-dir"}`, http.StatusNotImplemented)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, tesBasePath+"tasks/")
+	if rest == "" {
+		http.Error(w, `{"error":"task id required"}`, http.StatusBadRequest)
+		return
+	}
+
+	if strings.HasSuffix(rest, ":cancel") {
+		id := strings.TrimSuffix(rest, ":cancel")
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.tesManager.CancelTask(id); err != nil {
+			http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, map[string]string{})
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	task, found, err := s.tesStore.Get(rest)
+	if err != nil {
+		http.Error(w, `{"error":"failed to read task"}`, http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, `{"error":"task not found"}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, applyTESView(task, tesView(r.URL.Query().Get("view"))))
+}
+
+func tesView(v string) tes.View {
+	switch tes.View(v) {
+	case tes.ViewBasic, tes.ViewFull:
+		return tes.View(v)
+	default:
+		return tes.ViewMinimal
+	}
+}
+
+// applyTESView trims a task down to the fields its view permits, per the
+// GA4GH TES v1 spec: MINIMAL is id/state only, BASIC adds everything except
+// stdout/stderr and inline input content, FULL returns the task untouched.
+func applyTESView(task tes.Task, view tes.View) tes.Task {
+	if view == tes.ViewFull {
+		return task
+	}
+	if view == tes.ViewMinimal {
+		return tes.Task{ID: task.ID, State: task.State}
+	}
+
+	basic := task
+	for i := range basic.Inputs {
+		basic.Inputs[i].Content = ""
+	}
+	basic.Logs = make([]tes.TaskLog, len(task.Logs))
+	for i, l := range task.Logs {
+		basic.Logs[i] = l
+		basic.Logs[i].Logs = make([]tes.ExecutorLog, len(l.Logs))
+		for j, el := range l.Logs {
+			el.Stdout, el.Stderr = "", ""
+			basic.Logs[i].Logs[j] = el
+		}
+	}
+	return basic
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/homecloudhq/fnserve/server"
+
+// initTracer configures the global OpenTelemetry tracer provider to export
+// spans via OTLP/gRPC to otlpEndpoint. If otlpEndpoint is empty, tracing is a
+// no-op: otel.Tracer still works but spans are dropped. The returned shutdown
+// func flushes pending spans and must be called on server exit.
+func initTracer(ctx context.Context, otlpEndpoint string) (shutdown func(context.Context) error, err error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(otlpEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceNameKey.String("fnserve"),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
+
+// deriveTraceID deterministically maps an arbitrary legacy trace
+// identifier (fnserve's X-Trace-ID was historically a bare UUID, not a
+// W3C-shaped trace ID) onto a valid 16-byte trace.TraceID.
+func deriveTraceID(s string) (id trace.TraceID) {
+	if s == "" {
+		return id
+	}
+	sum := sha256.Sum256([]byte("traceid:" + s))
+	copy(id[:], sum[:16])
+	return id
+}
+
+// deriveSpanID deterministically maps a legacy X-Parent-Span value onto a
+// valid 8-byte trace.SpanID.
+func deriveSpanID(s string) (id trace.SpanID) {
+	if s == "" {
+		return id
+	}
+	sum := sha256.Sum256([]byte("spanid:" + s))
+	copy(id[:], sum[:8])
+	return id
+}
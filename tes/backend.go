@@ -0,0 +1,144 @@
+package tes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Backend fetches TES inputs and stores TES outputs for one URL scheme.
+type Backend interface {
+	// Fetch downloads url into the local file dest.
+	Fetch(ctx context.Context, url, dest string) error
+	// Store uploads the local file src to url.
+	Store(ctx context.Context, src, url string) error
+}
+
+// localBackend handles "file://" URLs and bare (scheme-less) paths by
+// copying directly on the local filesystem.
+type localBackend struct{}
+
+func (localBackend) Fetch(ctx context.Context, url, dest string) error {
+	return copyFile(strings.TrimPrefix(url, "file://"), dest)
+}
+
+func (localBackend) Store(ctx context.Context, src, url string) error {
+	dest := strings.TrimPrefix(url, "file://")
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+	return copyFile(src, dest)
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// s3Backend handles "s3://bucket/key" URLs.
+type s3Backend struct {
+	client *s3.Client
+}
+
+// newS3Backend builds an s3Backend using the default AWS credential chain
+// (environment, shared config, instance profile, ...). It returns an error
+// immediately if no usable configuration is found, rather than deferring the
+// failure to the first upload.
+func newS3Backend(ctx context.Context) (*s3Backend, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	// LoadDefaultConfig does not check credentials, so resolve them here.
+	if cfg.Credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials configured")
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+	}
+	return &s3Backend{client: s3.NewFromConfig(cfg)}, nil
+}
+
+func (b *s3Backend) Fetch(ctx context.Context, url, dest string) error {
+	bucket, key, err := parseS3URL(url)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+func (b *s3Backend) Store(ctx context.Context, src, url string) error {
+	bucket, key, err := parseS3URL(url)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = b.client.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket), Key: aws.String(key), Body: f})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", url, err)
+	}
+	return nil
+}
+
+func parseS3URL(url string) (bucket, key string, err error) {
+	rest := strings.TrimPrefix(url, "s3://")
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid s3 url %q: expected s3://bucket/key", url)
+	}
+	return parts[0], parts[1], nil
+}
+
+// backendFor resolves the Backend responsible for url's scheme. s3Client is
+// nil when no S3 backend could be configured, in which case s3:// URLs fail
+// with a clear error instead of a nil-pointer panic.
+func backendFor(url string, s3Client *s3Backend) (Backend, error) {
+	switch {
+	case strings.HasPrefix(url, "s3://"):
+		if s3Client == nil {
+			return nil, fmt.Errorf("s3:// output/input %q requires AWS credentials to be configured", url)
+		}
+		return s3Client, nil
+	default:
+		return localBackend{}, nil
+	}
+}
@@ -0,0 +1,335 @@
+package tes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Manager creates and runs TES tasks, persisting them to Store and
+// executing their executors either inside Docker (the default) or directly
+// on the host, mirroring how server.Server picks a runtime for functions.
+type Manager struct {
+	Store   *Store
+	WorkDir string // scratch root; each task gets WorkDir/<task-id>
+	Docker  bool   // run executors via `docker run` instead of exec.Command
+
+	s3 *s3Backend // nil until an s3:// input/output is actually needed
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewManager creates a Manager backed by store. workDir is created on
+// demand as tasks run.
+func NewManager(store *Store, workDir string, docker bool) *Manager {
+	return &Manager{
+		Store:   store,
+		WorkDir: workDir,
+		Docker:  docker,
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// CreateTask persists a new task in the QUEUED state and starts running it
+// in the background, returning its assigned ID immediately.
+func (m *Manager) CreateTask(task Task) (string, error) {
+	if len(task.Executors) == 0 {
+		return "", fmt.Errorf("task must have at least one executor")
+	}
+
+	task.ID = "task-" + uuid.NewString()
+	task.State = StateQueued
+	task.CreationTime = time.Now().UTC().Format(time.RFC3339)
+	if err := m.Store.Put(task); err != nil {
+		return "", fmt.Errorf("failed to persist task: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.cancels[task.ID] = cancel
+	m.mu.Unlock()
+
+	go m.run(ctx, task)
+
+	return task.ID, nil
+}
+
+// CancelTask marks a queued or running task CANCELED. It is a no-op for
+// tasks that have already reached a terminal state.
+func (m *Manager) CancelTask(id string) error {
+	task, found, err := m.Store.Get(id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("task %q not found", id)
+	}
+	if isTerminal(task.State) {
+		return nil
+	}
+
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	m.mu.Unlock()
+	if ok {
+		cancel()
+	}
+
+	task.State = StateCanceled
+	return m.Store.Put(task)
+}
+
+func isTerminal(s State) bool {
+	switch s {
+	case StateComplete, StateExecutorErr, StateSystemErr, StateCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// run materializes inputs, executes each executor in order, uploads
+// outputs, and persists the task's state at every transition so GET /tasks
+// reflects progress.
+func (m *Manager) run(ctx context.Context, task Task) {
+	defer func() {
+		m.mu.Lock()
+		delete(m.cancels, task.ID)
+		m.mu.Unlock()
+	}()
+
+	workDir := filepath.Join(m.WorkDir, task.ID)
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		m.fail(task, StateSystemErr, fmt.Sprintf("failed to create working directory: %v", err))
+		return
+	}
+
+	task.State = StateInitializing
+	if err := m.Store.Put(task); err != nil {
+		fmt.Printf("[tes] failed to persist task %s: %v\n", task.ID, err)
+	}
+
+	log := TaskLog{StartTime: time.Now().UTC().Format(time.RFC3339)}
+
+	if err := m.materializeInputs(ctx, task.Inputs, workDir); err != nil {
+		log.SystemLogs = append(log.SystemLogs, err.Error())
+		m.finish(task, log, StateSystemErr)
+		return
+	}
+
+	task.State = StateRunning
+	if err := m.Store.Put(task); err != nil {
+		fmt.Printf("[tes] failed to persist task %s: %v\n", task.ID, err)
+	}
+
+	finalState := StateComplete
+	for _, executor := range task.Executors {
+		execLog, err := m.runExecutor(ctx, executor, task.Resources, workDir)
+		log.Logs = append(log.Logs, execLog)
+		if ctx.Err() != nil {
+			m.finish(task, log, StateCanceled)
+			return
+		}
+		if err != nil || execLog.ExitCode != 0 {
+			finalState = StateExecutorErr
+			break
+		}
+	}
+
+	if finalState == StateComplete {
+		outputLogs, err := m.uploadOutputs(ctx, task.Outputs, workDir)
+		log.Outputs = outputLogs
+		if err != nil {
+			log.SystemLogs = append(log.SystemLogs, err.Error())
+			finalState = StateSystemErr
+		}
+	}
+
+	m.finish(task, log, finalState)
+}
+
+func (m *Manager) finish(task Task, log TaskLog, state State) {
+	log.EndTime = time.Now().UTC().Format(time.RFC3339)
+	task.Logs = append(task.Logs, log)
+	task.State = state
+	if err := m.Store.Put(task); err != nil {
+		fmt.Printf("[tes] failed to persist task %s: %v\n", task.ID, err)
+	}
+}
+
+func (m *Manager) fail(task Task, state State, msg string) {
+	m.finish(task, TaskLog{SystemLogs: []string{msg}}, state)
+}
+
+// materializeInputs downloads every Input with a URL, or writes inline
+// Content, into workDir at its relative Path.
+func (m *Manager) materializeInputs(ctx context.Context, inputs []Input, workDir string) error {
+	for _, in := range inputs {
+		dest := filepath.Join(workDir, in.Path)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return fmt.Errorf("failed to prepare input %s: %w", in.Path, err)
+		}
+
+		if in.Content != "" {
+			if err := os.WriteFile(dest, []byte(in.Content), 0o644); err != nil {
+				return fmt.Errorf("failed to write inline input %s: %w", in.Path, err)
+			}
+			continue
+		}
+
+		backend, err := m.backendFor(ctx, in.URL)
+		if err != nil {
+			return err
+		}
+		if err := backend.Fetch(ctx, in.URL, dest); err != nil {
+			return fmt.Errorf("failed to fetch input %s: %w", in.Path, err)
+		}
+	}
+	return nil
+}
+
+// uploadOutputs uploads every Output's Path from workDir to its URL.
+func (m *Manager) uploadOutputs(ctx context.Context, outputs []Output, workDir string) ([]OutputLog, error) {
+	var logs []OutputLog
+	for _, out := range outputs {
+		src := filepath.Join(workDir, out.Path)
+
+		backend, err := m.backendFor(ctx, out.URL)
+		if err != nil {
+			return logs, err
+		}
+		if err := backend.Store(ctx, src, out.URL); err != nil {
+			return logs, fmt.Errorf("failed to upload output %s: %w", out.Path, err)
+		}
+
+		size := "0"
+		if info, err := os.Stat(src); err == nil {
+			size = fmt.Sprintf("%d", info.Size())
+		}
+		logs = append(logs, OutputLog{URL: out.URL, Path: out.Path, SizeBytes: size})
+	}
+	return logs, nil
+}
+
+// backendFor lazily configures the S3 backend the first time it's needed,
+// so a server with no S3 inputs/outputs never has to have AWS credentials.
+func (m *Manager) backendFor(ctx context.Context, url string) (Backend, error) {
+	if len(url) >= 5 && url[:5] == "s3://" {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.s3 == nil {
+			s3c, err := newS3Backend(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to configure s3 backend: %w", err)
+			}
+			m.s3 = s3c
+		}
+		return backendFor(url, m.s3)
+	}
+	return backendFor(url, nil)
+}
+
+// runExecutor runs a single executor to completion, via Docker when
+// m.Docker is set and the executor names an image, or directly on the host
+// otherwise. Workdir, when set, is either an absolute path or a path
+// relative to the task's working directory; Stdin, when set, is a path
+// relative to the task's working directory whose contents are piped to the
+// executor's standard input. resources, when set, is passed through to the
+// Docker runtime as `-m`/`--cpus`; it has no effect outside Docker since the
+// host runtime has no equivalent cgroup-based limits to apply.
+func (m *Manager) runExecutor(ctx context.Context, executor Executor, resources *Resources, workDir string) (ExecutorLog, error) {
+	log := ExecutorLog{StartTime: time.Now().UTC().Format(time.RFC3339)}
+
+	var cmd *exec.Cmd
+	if m.Docker && executor.Image != "" {
+		containerWorkdir := "/workspace"
+		if executor.Workdir != "" {
+			if path.IsAbs(executor.Workdir) {
+				containerWorkdir = executor.Workdir
+			} else {
+				containerWorkdir = path.Join("/workspace", executor.Workdir)
+			}
+		}
+
+		args := []string{"run", "--rm", "-i", "-v", workDir + ":/workspace", "-w", containerWorkdir}
+		if resources != nil {
+			if resources.RAMGB > 0 {
+				args = append(args, "-m", fmt.Sprintf("%gg", resources.RAMGB))
+			}
+			if resources.CPUCores > 0 {
+				args = append(args, "--cpus", strconv.Itoa(resources.CPUCores))
+			}
+		}
+		for k, v := range executor.Env {
+			args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+		}
+		args = append(args, executor.Image)
+		args = append(args, executor.Command...)
+		cmd = exec.CommandContext(ctx, "docker", args...)
+	} else {
+		if len(executor.Command) == 0 {
+			log.EndTime = time.Now().UTC().Format(time.RFC3339)
+			log.ExitCode = -1
+			log.Stderr = "executor has no command"
+			return log, fmt.Errorf("executor has no command")
+		}
+		cmd = exec.CommandContext(ctx, executor.Command[0], executor.Command[1:]...)
+		cmd.Dir = workDir
+		if executor.Workdir != "" {
+			if filepath.IsAbs(executor.Workdir) {
+				cmd.Dir = executor.Workdir
+			} else {
+				cmd.Dir = filepath.Join(workDir, executor.Workdir)
+			}
+		}
+		cmd.Env = os.Environ()
+		for k, v := range executor.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+
+	if executor.Stdin != "" {
+		stdin, err := os.Open(filepath.Join(workDir, executor.Stdin))
+		if err != nil {
+			log.EndTime = time.Now().UTC().Format(time.RFC3339)
+			log.ExitCode = -1
+			log.Stderr = fmt.Sprintf("failed to open stdin %s: %v", executor.Stdin, err)
+			return log, fmt.Errorf("failed to open stdin %s: %w", executor.Stdin, err)
+		}
+		defer stdin.Close()
+		cmd.Stdin = stdin
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	log.EndTime = time.Now().UTC().Format(time.RFC3339)
+	log.Stdout = stdout.String()
+	log.Stderr = stderr.String()
+	if cmd.ProcessState != nil {
+		log.ExitCode = cmd.ProcessState.ExitCode()
+	} else {
+		log.ExitCode = -1
+	}
+
+	if executor.Stdout != "" {
+		os.WriteFile(filepath.Join(workDir, executor.Stdout), stdout.Bytes(), 0o644)
+	}
+	if executor.Stderr != "" {
+		os.WriteFile(filepath.Join(workDir, executor.Stderr), stderr.Bytes(), 0o644)
+	}
+
+	return log, err
+}
@@ -0,0 +1,123 @@
+package tes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketTasks = []byte("tes_tasks")
+
+// Store persists TES tasks to a BoltDB file so GET /tasks and GET
+// /tasks/{id} keep working after a restart. It lives alongside the async
+// job queue's database, under the same --queue-dir.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens (creating if necessary) the TES task database under dir.
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create tes dir: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "fnserve-tes.db"), 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tes store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketTasks)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize tes store: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put inserts or updates a task.
+func (s *Store) Put(task Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %s: %w", task.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTasks).Put([]byte(task.ID), data)
+	})
+}
+
+// Get looks up a task by ID.
+func (s *Store) Get(id string) (Task, bool, error) {
+	var task Task
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketTasks).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &task)
+	})
+	return task, found, err
+}
+
+// List returns tasks whose name has namePrefix (empty matches all), sorted
+// by ID, starting after pageToken (the ID of the last task from a previous
+// page, or "" for the first page). It returns at most pageSize tasks plus
+// the token to pass as pageToken for the next page ("" when there is none).
+func (s *Store) List(namePrefix string, pageSize int, pageToken string) ([]Task, string, error) {
+	var all []Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTasks).ForEach(func(_, v []byte) error {
+			var task Task
+			if err := json.Unmarshal(v, &task); err != nil {
+				return err
+			}
+			if namePrefix == "" || strings.HasPrefix(task.Name, namePrefix) {
+				all = append(all, task)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	start := 0
+	if pageToken != "" {
+		for i, task := range all {
+			if task.ID > pageToken {
+				start = i
+				break
+			}
+			start = i + 1
+		}
+	}
+	if start >= len(all) {
+		return nil, "", nil
+	}
+
+	if pageSize <= 0 {
+		pageSize = len(all) - start
+	}
+	end := start + pageSize
+	if end >= len(all) {
+		return all[start:], "", nil
+	}
+	return all[start:end], all[end-1].ID, nil
+}
@@ -0,0 +1,141 @@
+// Package tes implements a GA4GH Task Execution Service (TES) v1 compatible
+// HTTP API on top of fnserve, so existing TES clients and workflow engines
+// (e.g. Cromwell, Nextflow) can submit tasks to fnserve directly.
+package tes
+
+// State is a TES task's lifecycle state, per the GA4GH TES v1 spec.
+type State string
+
+const (
+	StateUnknown      State = "UNKNOWN"
+	StateQueued       State = "QUEUED"
+	StateInitializing State = "INITIALIZING"
+	StateRunning      State = "RUNNING"
+	StatePaused       State = "PAUSED"
+	StateComplete     State = "COMPLETE"
+	StateExecutorErr  State = "EXECUTOR_ERROR"
+	StateSystemErr    State = "SYSTEM_ERROR"
+	StateCanceled     State = "CANCELED"
+	StateCanceling    State = "CANCELING"
+)
+
+// View selects how much of a Task GetTask returns.
+type View string
+
+const (
+	ViewMinimal View = "MINIMAL"
+	ViewBasic   View = "BASIC"
+	ViewFull    View = "FULL"
+)
+
+// Task is a GA4GH TES v1 Task, as submitted via POST /tasks.
+type Task struct {
+	ID           string            `json:"id,omitempty"`
+	State        State             `json:"state,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Inputs       []Input           `json:"inputs,omitempty"`
+	Outputs      []Output          `json:"outputs,omitempty"`
+	Resources    *Resources        `json:"resources,omitempty"`
+	Executors    []Executor        `json:"executors"`
+	Volumes      []string          `json:"volumes,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	Logs         []TaskLog         `json:"logs,omitempty"`
+	CreationTime string            `json:"creation_time,omitempty"`
+}
+
+// Input describes a file or directory to materialize into the task's
+// working directory before its executors run.
+type Input struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Path        string `json:"path"`
+	Type        string `json:"type,omitempty"`
+	Content     string `json:"content,omitempty"`
+}
+
+// Output describes a file or directory to upload from the task's working
+// directory after its executors exit.
+type Output struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	Path        string `json:"path"`
+	Type        string `json:"type,omitempty"`
+}
+
+// Resources are advisory hints: CPUCores/RAMGB are passed through to the
+// Docker runtime as `--cpus`/`-m` when set (no effect outside Docker, and
+// ignored entirely by non-TES function invocations). DiskGB, Preemptible,
+// and Zones are accepted but not currently enforced or acted on.
+type Resources struct {
+	CPUCores    int      `json:"cpu_cores,omitempty"`
+	RAMGB       float64  `json:"ram_gb,omitempty"`
+	DiskGB      float64  `json:"disk_gb,omitempty"`
+	Preemptible bool     `json:"preemptible,omitempty"`
+	Zones       []string `json:"zones,omitempty"`
+}
+
+// Executor is a single command run inside the task's working directory.
+type Executor struct {
+	Image   string            `json:"image"`
+	Command []string          `json:"command"`
+	Workdir string            `json:"workdir,omitempty"`
+	Stdin   string            `json:"stdin,omitempty"`
+	Stdout  string            `json:"stdout,omitempty"`
+	Stderr  string            `json:"stderr,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// TaskLog records one attempt at running a task's executors.
+type TaskLog struct {
+	StartTime  string        `json:"start_time,omitempty"`
+	EndTime    string        `json:"end_time,omitempty"`
+	Logs       []ExecutorLog `json:"logs,omitempty"`
+	Outputs    []OutputLog   `json:"outputs,omitempty"`
+	SystemLogs []string      `json:"system_logs,omitempty"`
+}
+
+// ExecutorLog records the outcome of a single executor.
+type ExecutorLog struct {
+	StartTime string `json:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	Stdout    string `json:"stdout,omitempty"`
+	Stderr    string `json:"stderr,omitempty"`
+}
+
+// OutputLog records where an Output actually ended up.
+type OutputLog struct {
+	URL       string `json:"url"`
+	Path      string `json:"path"`
+	SizeBytes string `json:"size_bytes,omitempty"`
+}
+
+// ListView is the query-string view for GET /tasks; the list handler
+// accepts the same MINIMAL, BASIC and FULL values as View.
+type ListView = View
+
+// ListTasksResponse is the body of GET /tasks.
+type ListTasksResponse struct {
+	Tasks         []Task `json:"tasks"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// ServiceInfo is the body of GET /service-info.
+type ServiceInfo struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Doc              string   `json:"doc,omitempty"`
+	StorageEndpoints []string `json:"storage,omitempty"`
+	Type             Type     `json:"type"`
+}
+
+// Type identifies the service implementation per the GA4GH service-info
+// schema that TES embeds its type in.
+type Type struct {
+	Group    string `json:"group"`
+	Artifact string `json:"artifact"`
+	Version  string `json:"version"`
+}